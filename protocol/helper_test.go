@@ -0,0 +1,153 @@
+package protocol
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/0xPolygon/polygon-sdk/blockchain"
+	"github.com/0xPolygon/polygon-sdk/helper/tests"
+	"github.com/0xPolygon/polygon-sdk/network"
+	"github.com/0xPolygon/polygon-sdk/types"
+	"github.com/hashicorp/go-hclog"
+	"github.com/libp2p/go-libp2p-core/peer"
+)
+
+// NewRandomChain creates a blockchainShim with n blocks generated from a fixed
+// seed, so chains of different lengths share the same prefix
+func NewRandomChain(t *testing.T, n int) blockchainShim {
+	t.Helper()
+	headers := blockchain.NewTestHeaderChainWithSeed(nil, n, 0)
+	return blockchain.NewTestBlockchain(t, headers)
+}
+
+// NewMockBlockchain wraps a header slice into a minimal blockchainShim for tests
+func NewMockBlockchain(headers []*types.Header) blockchainShim {
+	bs := newMockBlockStore()
+
+	blocks := make([]*types.Block, len(headers))
+	for i, h := range headers {
+		blocks[i] = &types.Block{Header: h}
+	}
+	if err := bs.WriteBlocks(blocks); err != nil {
+		panic(err)
+	}
+	return bs
+}
+
+// GenerateNewBlocks appends count new blocks on top of chain's current head
+func GenerateNewBlocks(t *testing.T, chain blockchainShim, count int) []*types.Block {
+	t.Helper()
+
+	parent := chain.Header()
+	blocks := make([]*types.Block, count)
+	for i := 0; i < count; i++ {
+		h := &types.Header{
+			ParentHash: parent.Hash,
+			Number:     parent.Number + 1,
+			Difficulty: 1,
+		}
+		h.ComputeHash()
+		blocks[i] = &types.Block{Header: h}
+		parent = h
+	}
+
+	if err := chain.WriteBlocks(blocks); err != nil {
+		t.Fatalf("failed to write blocks: %v", err)
+	}
+	return blocks
+}
+
+// SetupSyncerNetwork wires up a local syncer and one syncer per peer chain,
+// joins them over the network and waits for the peer statuses to settle.
+func SetupSyncerNetwork(t *testing.T, chain blockchainShim, peerChains []blockchainShim) (*Syncer, []*Syncer) {
+	t.Helper()
+
+	conf := func(c *network.Config) {
+		c.NoDiscover = true
+	}
+
+	server := network.CreateServer(t, conf)
+	syncer := NewSyncer(hclog.NewNullLogger(), server, chain)
+	syncer.Start()
+
+	peerSyncers := make([]*Syncer, len(peerChains))
+	for i, peerChain := range peerChains {
+		peerServer := network.CreateServer(t, conf)
+		peerSyncer := NewSyncer(hclog.NewNullLogger(), peerServer, peerChain)
+		peerSyncer.Start()
+
+		network.MultiJoin(t, server, peerServer)
+		peerSyncers[i] = peerSyncer
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if _, err := WaitUntilSyncPeersNumber(ctx, syncer, int64(len(peerChains))); err != nil {
+		t.Fatalf("failed to connect peers: %v", err)
+	}
+
+	return syncer, peerSyncers
+}
+
+// WaitUntilPeerConnected blocks until syncer has exactly num tracked peers
+func WaitUntilPeerConnected(t *testing.T, syncer *Syncer, num int, timeout time.Duration) {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if _, err := WaitUntilSyncPeersNumber(ctx, syncer, int64(num)); err != nil {
+		t.Fatalf("failed to wait for %d peers: %v", num, err)
+	}
+}
+
+// TryPopBlock pops a single broadcast block off the given peer's queue
+func TryPopBlock(t *testing.T, syncer *Syncer, id peer.ID, timeout time.Duration) (*types.Block, bool) {
+	t.Helper()
+
+	p := getPeer(syncer, id)
+	if p == nil {
+		return nil, false
+	}
+	return p.PopBlock(timeout)
+}
+
+// numSyncPeers returns the number of sync peers
+func numSyncPeers(syncer *Syncer) int64 {
+	return int64(syncer.peerSet.Len())
+}
+
+// WaitUntilSyncPeersNumber waits until the number of sync peers reaches a certain number, otherwise it times out
+func WaitUntilSyncPeersNumber(ctx context.Context, syncer *Syncer, requiredNum int64) (int64, error) {
+	res, err := tests.RetryUntilTimeout(ctx, func() (interface{}, bool) {
+		numPeers := numSyncPeers(syncer)
+		if numPeers == requiredNum {
+			return numPeers, false
+		}
+		return nil, true
+	})
+
+	if err != nil {
+		return 0, err
+	}
+	return res.(int64), nil
+}
+
+// WaitUntilProcessedAllEvents blocks until syncer has drained its blockchain event queue
+func WaitUntilProcessedAllEvents(t *testing.T, syncer *Syncer, timeout time.Duration) {
+	t.Helper()
+
+	done := make(chan struct{})
+	select {
+	case syncer.flushCh <- done:
+	case <-time.After(timeout):
+		t.Fatal("timed out sending flush request")
+		return
+	}
+
+	select {
+	case <-done:
+	case <-time.After(timeout):
+		t.Fatal("timed out waiting for events to be processed")
+	}
+}
@@ -0,0 +1,250 @@
+package peers
+
+import (
+	"container/heap"
+	"sync"
+
+	"github.com/0xPolygon/polygon-sdk/types"
+	"github.com/libp2p/go-libp2p-core/peer"
+)
+
+// HeapPeerSet is a PeerSet backed by a max-heap keyed on (blockNumber,
+// trustScore), giving Best an O(1) lookup in the common case - the root
+// already qualifies - instead of mapPeerSet's O(N) scan. When it must fall
+// through an untrusted root it first copies the heap, so that case is O(N)
+// as well. Intended for
+// deployments with very large peer counts.
+type HeapPeerSet struct {
+	mu      sync.Mutex
+	entries peerHeap
+	index   map[peer.ID]*heapEntry
+}
+
+type heapEntry struct {
+	peer  *Peer
+	index int
+}
+
+type peerHeap []*heapEntry
+
+func (h peerHeap) Len() int { return len(h) }
+
+func (h peerHeap) Less(i, j int) bool {
+	si, sj := h[i].peer.Status(), h[j].peer.Status()
+	switch {
+	case si == nil && sj == nil:
+		return false
+	case si == nil:
+		return false
+	case sj == nil:
+		return true
+	case si.Number != sj.Number:
+		return si.Number > sj.Number
+	default:
+		return h[i].peer.Trust() > h[j].peer.Trust()
+	}
+}
+
+func (h peerHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *peerHeap) Push(x interface{}) {
+	e := x.(*heapEntry)
+	e.index = len(*h)
+	*h = append(*h, e)
+}
+
+func (h *peerHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	e := old[n-1]
+	old[n-1] = nil
+	*h = old[:n-1]
+	return e
+}
+
+// NewHeapPeerSet creates a heap-backed PeerSet, tuned for large peer counts.
+func NewHeapPeerSet() PeerSet {
+	return &HeapPeerSet{
+		index: make(map[peer.ID]*heapEntry),
+	}
+}
+
+func (s *HeapPeerSet) Add(id peer.ID, p *Peer) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if e, ok := s.index[id]; ok {
+		e.peer = p
+		heap.Fix(&s.entries, e.index)
+		return
+	}
+
+	e := &heapEntry{peer: p}
+	heap.Push(&s.entries, e)
+	s.index[id] = e
+}
+
+func (s *HeapPeerSet) Remove(id peer.ID) (*Peer, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.index[id]
+	if !ok {
+		return nil, false
+	}
+	heap.Remove(&s.entries, e.index)
+	delete(s.index, id)
+	return e.peer, true
+}
+
+func (s *HeapPeerSet) Get(id peer.ID) (*Peer, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.index[id]
+	if !ok {
+		return nil, false
+	}
+	return e.peer, true
+}
+
+// fix re-establishes the heap property for id after its status or trust changed.
+func (s *HeapPeerSet) fix(id peer.ID) {
+	if e, ok := s.index[id]; ok {
+		heap.Fix(&s.entries, e.index)
+	}
+}
+
+// Best returns the tallest sufficiently trusted peer. The heap root is the
+// tallest peer overall; the common case is that it also qualifies on trust,
+// which Best answers straight off the root in O(1). Only when the root is
+// untrusted does it fall through the rest of the heap - in heap order, not
+// index order - until it finds one that qualifies, matching mapPeerSet.Best's
+// semantics of excluding untrusted peers rather than giving up as soon as the
+// tallest one doesn't qualify.
+func (s *HeapPeerSet) Best(minHeight uint64) *Peer {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.entries) == 0 {
+		return nil
+	}
+
+	root := s.entries[0].peer
+	status := root.Status()
+	if status == nil || status.Number <= minHeight {
+		// the heap is ordered by (blockNumber, trustScore): if the tallest
+		// peer overall doesn't clear minHeight, nothing else in the heap can.
+		return nil
+	}
+	if root.Trust() >= MinTrustScore {
+		return root
+	}
+
+	// the root is untrusted and must be skipped. scratch copies wrap the same
+	// *Peer but are fresh *heapEntry values, so popping from remaining can't
+	// mutate the real entries' heap indices.
+	remaining := make(peerHeap, len(s.entries))
+	for i, e := range s.entries {
+		remaining[i] = &heapEntry{peer: e.peer}
+	}
+
+	for len(remaining) > 0 {
+		top := heap.Pop(&remaining).(*heapEntry).peer
+		status := top.Status()
+		if status == nil || status.Number <= minHeight {
+			// the heap is ordered by (blockNumber, trustScore): once the
+			// remaining root's height no longer clears minHeight, nothing
+			// left in the heap can either.
+			return nil
+		}
+		if top.Trust() >= MinTrustScore {
+			return top
+		}
+	}
+	return nil
+}
+
+func (s *HeapPeerSet) AllAbove(height uint64) []*Peer {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := make([]*Peer, 0, len(s.entries))
+	for _, e := range s.entries {
+		status := e.peer.Status()
+		if status != nil && status.Number > height && e.peer.Trust() >= MinTrustScore {
+			result = append(result, e.peer)
+		}
+	}
+	return result
+}
+
+func (s *HeapPeerSet) MarkBad(id peer.ID, reason string) bool {
+	s.mu.Lock()
+	e, ok := s.index[id]
+	if !ok {
+		s.mu.Unlock()
+		return false
+	}
+	e.peer.penalizeTrust()
+	heap.Fix(&s.entries, e.index)
+	belowThreshold := e.peer.Trust() < MinTrustScore
+	s.mu.Unlock()
+
+	if belowThreshold {
+		s.Remove(id)
+		return true
+	}
+	return false
+}
+
+func (s *HeapPeerSet) MarkGood(id peer.ID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.index[id]
+	if !ok {
+		return
+	}
+	e.peer.rewardTrust()
+	heap.Fix(&s.entries, e.index)
+}
+
+func (s *HeapPeerSet) BroadcastExcept(b *types.Block, except peer.ID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id, e := range s.index {
+		if id == except {
+			continue
+		}
+		e.peer.SetStatus(&Status{Hash: b.Header.Hash, Number: b.Header.Number})
+		e.peer.Enqueue(b)
+		heap.Fix(&s.entries, e.index)
+	}
+}
+
+func (s *HeapPeerSet) Iterate(fn func(id peer.ID, p *Peer) bool) {
+	s.mu.Lock()
+	snapshot := make(map[peer.ID]*Peer, len(s.index))
+	for id, e := range s.index {
+		snapshot[id] = e.peer
+	}
+	s.mu.Unlock()
+
+	for id, p := range snapshot {
+		if !fn(id, p) {
+			return
+		}
+	}
+}
+
+func (s *HeapPeerSet) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.entries)
+}
@@ -0,0 +1,146 @@
+package peers
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/0xPolygon/polygon-sdk/types"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/stretchr/testify/assert"
+)
+
+// simulatedPeers populates a PeerSet with n peers at increasing heights, none
+// of which hold a live gRPC connection, so Best/BroadcastExcept exercise only
+// the bookkeeping under test.
+func simulatedPeers(t *testing.T, set PeerSet, n int) []peer.ID {
+	t.Helper()
+
+	ids := make([]peer.ID, n)
+	for i := 0; i < n; i++ {
+		id := peer.ID(fmt.Sprintf("sim-peer-%d", i))
+		ids[i] = id
+
+		p := NewPeer(id, nil)
+		p.SetStatus(&Status{Number: uint64(i)})
+		set.Add(id, p)
+	}
+	return ids
+}
+
+func TestHeapPeerSet_BestMatchesMapPeerSetUnderLoad(t *testing.T) {
+	const numPeers = 10000
+
+	mapSet := NewMapPeerSet()
+	heapSet := NewHeapPeerSet()
+
+	ids := simulatedPeers(t, mapSet, numPeers)
+	simulatedPeers(t, heapSet, numPeers)
+	// simulatedPeers assigns the same heights to both sets via the same
+	// deterministic index order, so their Best() picks must agree.
+	_ = ids
+
+	mapBest := mapSet.Best(0)
+	heapBest := heapSet.Best(0)
+
+	assert.NotNil(t, mapBest)
+	assert.NotNil(t, heapBest)
+	assert.Equal(t, mapBest.Status().Number, heapBest.Status().Number)
+}
+
+// TestHeapPeerSet_BestSkipsUntrustedRoot demonstrates the behavior Best's
+// fall-through exists for: a wall-clock comparison against mapPeerSet would
+// flake under CI load, so this instead pins the single tallest peer as
+// untrusted (but still tracked) and checks Best still returns the
+// next-tallest trusted peer in a heap of realistic size, rather than giving
+// up as soon as the root doesn't qualify.
+func TestHeapPeerSet_BestSkipsUntrustedRoot(t *testing.T) {
+	const numPeers = 10000
+
+	heapSet := NewHeapPeerSet().(*HeapPeerSet)
+	ids := simulatedPeers(t, heapSet, numPeers)
+
+	tallestID := ids[len(ids)-1]
+	tallest, _ := heapSet.Get(tallestID)
+	for tallest.Trust() >= MinTrustScore {
+		tallest.penalizeTrust()
+	}
+	heapSet.fix(tallestID)
+
+	best := heapSet.Best(0)
+	assert.NotNil(t, best)
+	assert.GreaterOrEqual(t, best.Trust(), int64(MinTrustScore))
+	assert.Equal(t, uint64(len(ids)-2), best.Status().Number)
+}
+
+// BenchmarkHeapPeerSet_Best and BenchmarkMapPeerSet_Best demonstrate the perf
+// win HeapPeerSet's doc comment claims: with a qualifying root, Best answers
+// in O(1) instead of mapPeerSet's O(N) scan, so the gap between the two
+// should widen with numPeers rather than stay flat.
+func BenchmarkHeapPeerSet_Best(b *testing.B) {
+	const numPeers = 10000
+
+	set := NewHeapPeerSet()
+	for i := 0; i < numPeers; i++ {
+		id := peer.ID(fmt.Sprintf("sim-peer-%d", i))
+		p := NewPeer(id, nil)
+		p.SetStatus(&Status{Number: uint64(i)})
+		set.Add(id, p)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		set.Best(0)
+	}
+}
+
+func BenchmarkMapPeerSet_Best(b *testing.B) {
+	const numPeers = 10000
+
+	set := NewMapPeerSet()
+	for i := 0; i < numPeers; i++ {
+		id := peer.ID(fmt.Sprintf("sim-peer-%d", i))
+		p := NewPeer(id, nil)
+		p.SetStatus(&Status{Number: uint64(i)})
+		set.Add(id, p)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		set.Best(0)
+	}
+}
+
+func TestHeapPeerSet_MarkBadRemovesBelowThreshold(t *testing.T) {
+	set := NewHeapPeerSet()
+	ids := simulatedPeers(t, set, 5)
+
+	id := ids[len(ids)-1]
+	var removed bool
+	for i := 0; i <= -MinTrustScore; i++ {
+		removed = set.MarkBad(id, "test penalty")
+	}
+
+	assert.True(t, removed)
+	_, ok := set.Get(id)
+	assert.False(t, ok)
+	assert.Equal(t, 4, set.Len())
+}
+
+func TestHeapPeerSet_BroadcastExceptSkipsExcludedPeer(t *testing.T) {
+	set := NewHeapPeerSet()
+	ids := simulatedPeers(t, set, 3)
+
+	block := &types.Block{Header: &types.Header{Number: 100}}
+	block.Header.ComputeHash()
+
+	set.BroadcastExcept(block, ids[0])
+
+	excluded, _ := set.Get(ids[0])
+	assert.Equal(t, 0, excluded.QueueLen())
+
+	for _, id := range ids[1:] {
+		p, _ := set.Get(id)
+		assert.Equal(t, 1, p.QueueLen())
+		assert.Equal(t, block.Header.Number, p.Status().Number)
+	}
+}
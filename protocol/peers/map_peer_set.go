@@ -0,0 +1,135 @@
+package peers
+
+import (
+	"sync"
+
+	"github.com/0xPolygon/polygon-sdk/types"
+	"github.com/libp2p/go-libp2p-core/peer"
+)
+
+// mapPeerSet is the default PeerSet, backed by a sync.Map. Add/Remove/Get are
+// O(1); Best and AllAbove are O(N) since every peer's status must be read to
+// compare against the rest. Good enough for the peer counts a single node
+// normally connects to; see HeapPeerSet for large deployments.
+type mapPeerSet struct {
+	peers sync.Map // map[peer.ID]*Peer
+}
+
+// NewMapPeerSet creates the default, sync.Map-backed PeerSet.
+func NewMapPeerSet() PeerSet {
+	return &mapPeerSet{}
+}
+
+func (s *mapPeerSet) Add(id peer.ID, p *Peer) {
+	s.peers.Store(id, p)
+}
+
+func (s *mapPeerSet) Remove(id peer.ID) (*Peer, bool) {
+	v, ok := s.peers.LoadAndDelete(id)
+	if !ok {
+		return nil, false
+	}
+	return v.(*Peer), true
+}
+
+func (s *mapPeerSet) Get(id peer.ID) (*Peer, bool) {
+	v, ok := s.peers.Load(id)
+	if !ok {
+		return nil, false
+	}
+	return v.(*Peer), true
+}
+
+func (s *mapPeerSet) Best(minHeight uint64) *Peer {
+	var best *Peer
+
+	s.peers.Range(func(_, value interface{}) bool {
+		p := value.(*Peer)
+		status := p.Status()
+		if status == nil || status.Number <= minHeight {
+			return true
+		}
+		if p.Trust() < MinTrustScore {
+			return true
+		}
+		if best == nil || isBetter(p, best) {
+			best = p
+		}
+		return true
+	})
+
+	return best
+}
+
+func isBetter(candidate, current *Peer) bool {
+	candidateNumber, currentNumber := candidate.Status().Number, current.Status().Number
+	if candidateNumber != currentNumber {
+		return candidateNumber > currentNumber
+	}
+	return candidate.Trust() > current.Trust()
+}
+
+func (s *mapPeerSet) AllAbove(height uint64) []*Peer {
+	var result []*Peer
+
+	s.peers.Range(func(_, value interface{}) bool {
+		p := value.(*Peer)
+		status := p.Status()
+		if status != nil && status.Number > height && p.Trust() >= MinTrustScore {
+			result = append(result, p)
+		}
+		return true
+	})
+
+	return result
+}
+
+func (s *mapPeerSet) MarkBad(id peer.ID, reason string) bool {
+	v, ok := s.peers.Load(id)
+	if !ok {
+		return false
+	}
+	p := v.(*Peer)
+	p.penalizeTrust()
+
+	if p.Trust() < MinTrustScore {
+		s.peers.Delete(id)
+		return true
+	}
+	return false
+}
+
+func (s *mapPeerSet) MarkGood(id peer.ID) {
+	v, ok := s.peers.Load(id)
+	if !ok {
+		return
+	}
+	v.(*Peer).rewardTrust()
+}
+
+func (s *mapPeerSet) BroadcastExcept(b *types.Block, except peer.ID) {
+	s.peers.Range(func(key, value interface{}) bool {
+		if key.(peer.ID) == except {
+			return true
+		}
+		p := value.(*Peer)
+		p.SetStatus(&Status{Hash: b.Header.Hash, Number: b.Header.Number})
+		p.Enqueue(b)
+		return true
+	})
+}
+
+func (s *mapPeerSet) Iterate(fn func(id peer.ID, p *Peer) bool) {
+	s.peers.Range(func(key, value interface{}) bool {
+		return fn(key.(peer.ID), value.(*Peer))
+	})
+}
+
+func (s *mapPeerSet) Len() int {
+	n := 0
+	s.peers.Range(func(_, _ interface{}) bool {
+		n++
+		return true
+	})
+	return n
+}
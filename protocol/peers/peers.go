@@ -0,0 +1,175 @@
+// Package peers holds the sync-peer bookkeeping that used to live directly
+// inside protocol.Syncer: the set of connected peers, their advertised chain
+// status, their broadcast queues and their trust scores.
+package peers
+
+import (
+	"sync"
+	"time"
+
+	"github.com/0xPolygon/polygon-sdk/protocol/proto"
+	"github.com/0xPolygon/polygon-sdk/types"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"google.golang.org/grpc"
+)
+
+const (
+	// maxEnqueueSize bounds how many broadcast blocks are buffered per peer
+	// before the oldest ones are dropped, so a slow peer can't grow unbounded memory.
+	maxEnqueueSize = 10
+
+	// InitialTrustScore is where every peer's trust score starts out at.
+	InitialTrustScore = 0
+	// MinTrustScore is the threshold below which a peer is excluded from Best
+	// and AllAbove, and should be disconnected by the caller.
+	MinTrustScore = -3
+	// TrustScoreStep is how much a single good or bad interaction moves the score.
+	TrustScoreStep = 1
+)
+
+// Status is a peer's (or the local node's) advertised chain head.
+type Status struct {
+	Hash   types.Hash
+	Number uint64
+}
+
+// Copy makes a copy of the status
+func (s *Status) Copy() *Status {
+	return &Status{Hash: s.Hash, Number: s.Number}
+}
+
+// Peer is the local view of a connected peer taking part in the sync protocol.
+type Peer struct {
+	id     peer.ID
+	conn   *grpc.ClientConn
+	client proto.V1Client
+
+	statusLock sync.Mutex
+	status     *Status
+
+	enqueue chan *types.Block
+
+	trustScore int64
+}
+
+// NewPeer creates a Peer wrapping a live gRPC connection to id.
+func NewPeer(id peer.ID, conn *grpc.ClientConn) *Peer {
+	return &Peer{
+		id:         id,
+		conn:       conn,
+		client:     proto.NewV1Client(conn),
+		enqueue:    make(chan *types.Block, maxEnqueueSize),
+		trustScore: InitialTrustScore,
+	}
+}
+
+// ID returns the peer's network identity.
+func (p *Peer) ID() peer.ID {
+	return p.id
+}
+
+// Client returns the sync protocol's gRPC client for this peer.
+func (p *Peer) Client() proto.V1Client {
+	return p.client
+}
+
+// Close tears down the peer's connection.
+func (p *Peer) Close() error {
+	return p.conn.Close()
+}
+
+// Status returns the peer's last known chain head.
+func (p *Peer) Status() *Status {
+	p.statusLock.Lock()
+	defer p.statusLock.Unlock()
+	return p.status
+}
+
+// SetStatus updates the peer's last known chain head.
+func (p *Peer) SetStatus(status *Status) {
+	p.statusLock.Lock()
+	defer p.statusLock.Unlock()
+	p.status = status
+}
+
+// Enqueue appends a broadcast block to the peer's outbound queue, dropping the
+// oldest queued block if it's full.
+func (p *Peer) Enqueue(b *types.Block) {
+	select {
+	case p.enqueue <- b:
+	default:
+		<-p.enqueue
+		p.enqueue <- b
+	}
+}
+
+// PopBlock blocks until a broadcast block is available, or timeout elapses.
+func (p *Peer) PopBlock(timeout time.Duration) (*types.Block, bool) {
+	select {
+	case b := <-p.enqueue:
+		return b, true
+	case <-time.After(timeout):
+		return nil, false
+	}
+}
+
+// QueueLen returns the number of blocks currently queued for this peer.
+func (p *Peer) QueueLen() int {
+	return len(p.enqueue)
+}
+
+// Trust returns the peer's current trust score.
+func (p *Peer) Trust() int64 {
+	p.statusLock.Lock()
+	defer p.statusLock.Unlock()
+	return p.trustScore
+}
+
+func (p *Peer) rewardTrust() {
+	p.statusLock.Lock()
+	p.trustScore += TrustScoreStep
+	p.statusLock.Unlock()
+}
+
+func (p *Peer) penalizeTrust() {
+	p.statusLock.Lock()
+	p.trustScore -= TrustScoreStep
+	p.statusLock.Unlock()
+}
+
+// PenalizeTrust is called when the peer serves an invalid header or times out.
+func (p *Peer) PenalizeTrust() {
+	p.penalizeTrust()
+}
+
+// PeerSet tracks every peer connected to the local sync protocol instance.
+// Syncer depends on this interface rather than a concrete type so alternative
+// implementations (e.g. a heap keyed on height) can be plugged in for large
+// peer counts.
+type PeerSet interface {
+	// Add starts tracking p under id.
+	Add(id peer.ID, p *Peer)
+	// Remove stops tracking id, returning the removed peer if it was present.
+	Remove(id peer.ID) (*Peer, bool)
+	// Get returns the tracked peer for id, if any.
+	Get(id peer.ID) (*Peer, bool)
+	// Best returns the tracked, sufficiently trusted peer furthest ahead of
+	// minHeight, breaking ties by trust score. Returns nil if none qualify.
+	Best(minHeight uint64) *Peer
+	// AllAbove returns every tracked, sufficiently trusted peer whose status
+	// is strictly ahead of height.
+	AllAbove(height uint64) []*Peer
+	// MarkBad penalizes id's trust score for reason, removing it from the set
+	// if it drops below MinTrustScore. Returns true if the peer was removed.
+	MarkBad(id peer.ID, reason string) bool
+	// MarkGood rewards id's trust score after a successful interaction (e.g. a
+	// completed bulk sync), keeping any ordering the set maintains on trust
+	// score up to date.
+	MarkGood(id peer.ID)
+	// BroadcastExcept enqueues b on every tracked peer other than except.
+	BroadcastExcept(b *types.Block, except peer.ID)
+	// Iterate visits every tracked peer until fn returns false.
+	Iterate(fn func(id peer.ID, p *Peer) bool)
+	// Len returns the number of tracked peers.
+	Len() int
+}
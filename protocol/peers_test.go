@@ -0,0 +1,199 @@
+package protocol
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/0xPolygon/polygon-sdk/network"
+	"github.com/0xPolygon/polygon-sdk/types"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandleNewPeer(t *testing.T) {
+	tests := []struct {
+		name       string
+		chain      blockchainShim
+		peerChains []blockchainShim
+	}{
+		{
+			name:  "should set peer's status",
+			chain: NewRandomChain(t, 5),
+			peerChains: []blockchainShim{
+				NewRandomChain(t, 5),
+				NewRandomChain(t, 10),
+				NewRandomChain(t, 15),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			syncer, peerSyncers := SetupSyncerNetwork(t, tt.chain, tt.peerChains)
+
+			// Check peer's status in Syncer's peer list
+			for _, peerSyncer := range peerSyncers {
+				peer := getPeer(syncer, peerSyncer.server.AddrInfo().ID)
+				assert.NotNil(t, peer, "syncer must have peer's status, but nil")
+
+				// should receive latest status
+				expectedStatus := GetCurrentStatus(peerSyncer.blockchain)
+				assert.Equal(t, expectedStatus, peer.Status())
+			}
+		})
+	}
+}
+
+func TestDeletePeer(t *testing.T) {
+	tests := []struct {
+		name                 string
+		chain                blockchainShim
+		peerChains           []blockchainShim
+		numDisconnectedPeers int
+	}{
+		{
+			name:  "should not have data in peers for disconnected peer",
+			chain: NewRandomChain(t, 5),
+			peerChains: []blockchainShim{
+				NewRandomChain(t, 5),
+				NewRandomChain(t, 10),
+				NewRandomChain(t, 15),
+			},
+			numDisconnectedPeers: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			syncer, peerSyncers := SetupSyncerNetwork(t, tt.chain, tt.peerChains)
+
+			// disconnects from syncer
+			for i := 0; i < tt.numDisconnectedPeers; i++ {
+				peerSyncers[i].server.Disconnect(syncer.server.AddrInfo().ID, "bye")
+			}
+			WaitUntilPeerConnected(t, syncer, len(tt.peerChains)-tt.numDisconnectedPeers, 10*time.Second)
+
+			for idx, peerSyncer := range peerSyncers {
+				shouldBeDeleted := idx < tt.numDisconnectedPeers
+				peer := getPeer(syncer, peerSyncer.server.AddrInfo().ID)
+				if shouldBeDeleted {
+					assert.Nil(t, peer)
+				} else {
+					assert.NotNil(t, peer)
+				}
+			}
+		})
+	}
+}
+
+func createGenesisBlock() []*types.Block {
+	blocks := make([]*types.Block, 0)
+	genesis := &types.Header{Difficulty: 1, Number: 0}
+	genesis.ComputeHash()
+	b := &types.Block{
+		Header: genesis,
+	}
+	blocks = append(blocks, b)
+	return blocks
+}
+
+func createBlockStores(count int) (bStore []*mockBlockStore) {
+	bStore = make([]*mockBlockStore, count)
+	for i := 0; i < count; i++ {
+		bStore[i] = newMockBlockStore()
+	}
+	return
+}
+
+// createNetworkServers is a helper function for generating network servers
+func createNetworkServers(count int, t *testing.T, conf func(c *network.Config)) []*network.Server {
+	networkServers := make([]*network.Server, count)
+
+	for indx := 0; indx < count; indx++ {
+		networkServers[indx] = network.CreateServer(t, conf)
+	}
+
+	return networkServers
+}
+
+// createSyncers is a helper function for generating syncers. Servers and BlockStores should be at least the length
+// of count
+func createSyncers(count int, servers []*network.Server, blockStores []*mockBlockStore) []*Syncer {
+	syncers := make([]*Syncer, count)
+
+	for indx := 0; indx < count; indx++ {
+		syncers[indx] = NewSyncer(hclog.NewNullLogger(), servers[indx], blockStores[indx])
+	}
+
+	return syncers
+}
+
+func TestSyncer_PeerDisconnected(t *testing.T) {
+	conf := func(c *network.Config) {
+		c.MaxPeers = 4
+		c.NoDiscover = true
+	}
+	blocks := createGenesisBlock()
+
+	// Create three servers
+	servers := createNetworkServers(3, t, conf)
+
+	// Create the block stores
+	blockStores := createBlockStores(3)
+
+	for _, blockStore := range blockStores {
+		assert.NoError(t, blockStore.WriteBlocks(blocks))
+	}
+
+	// Create the syncers
+	syncers := createSyncers(3, servers, blockStores)
+
+	// Start the syncers
+	for _, syncer := range syncers {
+		go syncer.Start()
+	}
+
+	network.MultiJoin(
+		t,
+		servers[0],
+		servers[1],
+		servers[0],
+		servers[2],
+		servers[1],
+		servers[2],
+	)
+
+	// wait until gossip protocol builds the mesh network (https://github.com/libp2p/specs/blob/master/pubsub/gossipsub/gossipsub-v1.0.md)
+	waitCtx, cancelWait := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancelWait()
+
+	numPeers, err := WaitUntilSyncPeersNumber(waitCtx, syncers[1], 2)
+	if err != nil {
+		t.Fatalf("Unable to add sync peers, %v", err)
+	}
+	// Make sure the number of peers is correct
+	// -1 to exclude the current node
+	assert.Equal(t, int64(len(servers)-1), numPeers)
+
+	// Disconnect peer2
+	peerToDisconnect := servers[2].AddrInfo().ID
+	servers[1].Disconnect(peerToDisconnect, "testing")
+
+	waitCtx, cancelWait = context.WithTimeout(context.Background(), time.Second*10)
+	defer cancelWait()
+	numPeers, err = WaitUntilSyncPeersNumber(waitCtx, syncers[1], 1)
+	if err != nil {
+		t.Fatalf("Unable to disconnect sync peers, %v", err)
+	}
+	// Make sure a single peer disconnected
+	// Additional -1 to exclude the current node
+	assert.Equal(t, int64(len(servers)-2), numPeers)
+
+	// server1 syncer should have disconnected from server2 peer
+	_, found := syncers[1].peerSet.Get(peerToDisconnect)
+
+	// Make sure that the disconnected peer is not in the
+	// reference node's sync peer map
+	assert.False(t, found)
+}
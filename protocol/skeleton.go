@@ -0,0 +1,271 @@
+package protocol
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/0xPolygon/polygon-sdk/types"
+)
+
+const (
+	// skeletonInterval is the spacing, in block numbers, between consecutive
+	// skeleton header anchors fetched from the main sync peer.
+	skeletonInterval = 128
+
+	// pivotGap is how many blocks behind the main peer's head the skeleton stops,
+	// leaving a safety margin that WatchSyncWithPeer takes over for.
+	pivotGap = 64
+
+	// numSkeletonWorkers bounds how many sub-tasks are downloaded concurrently.
+	numSkeletonWorkers = 4
+)
+
+var (
+	// errNoSyncPeer is returned when no peer is both connected and ahead of the local chain
+	errNoSyncPeer = errors.New("no suitable sync peer found")
+	// errSkeletonSize is returned when the main peer's skeleton has fewer than two anchors
+	errSkeletonSize = errors.New("skeleton must contain at least two header anchors")
+	// errNoMainSkeleton is returned when the skeleton can't be fetched from the main peer at all
+	errNoMainSkeleton = errors.New("failed to fetch header skeleton from main peer")
+)
+
+// skeletonTask is the unit of work distributed to the worker pool: download and
+// verify every block after the lower skeleton anchor up to and including the
+// upper one.
+type skeletonTask struct {
+	index int
+	from  *types.Header // exclusive lower anchor: the local head for the first task, the previous task's upper anchor otherwise
+	to    *types.Header // inclusive upper anchor
+}
+
+type skeletonResult struct {
+	index  int
+	blocks []*types.Block
+}
+
+// FastSyncWithPeers downloads the chain in parallel from every connected peer,
+// using a skeleton of headers fetched from a single trusted "main" peer to
+// carve the work into independently verifiable sub-tasks. The skeleton stops
+// pivotGap blocks behind main's head; that trailing range is fetched directly
+// before handing off to WatchSyncWithPeer for whatever gossips in afterwards,
+// since WatchSyncWithPeer only drains already-broadcast blocks and can't
+// backfill a historical gap on its own.
+func (s *Syncer) FastSyncWithPeers() error {
+	main := s.BestPeer()
+	if main == nil {
+		return errNoSyncPeer
+	}
+
+	skeleton, err := s.fetchSkeleton(main)
+	if err != nil {
+		return err
+	}
+	if len(skeleton) < 2 {
+		return errSkeletonSize
+	}
+
+	results, err := s.runSkeletonTasks(skeleton)
+	if err != nil {
+		return err
+	}
+
+	for _, res := range results {
+		if err := s.blockchain.WriteBlocks(res.blocks); err != nil {
+			return err
+		}
+	}
+
+	s.statusLock.Lock()
+	s.status = HeaderToStatus(skeleton[len(skeleton)-1])
+	s.statusLock.Unlock()
+
+	pivotStatus := main.Status()
+	lastAnchor := skeleton[len(skeleton)-1]
+	if pivotStatus.Number > lastAnchor.Number {
+		blocks, err := getBlocks(main.Client(), lastAnchor.Number+1, pivotStatus.Number)
+		if err != nil {
+			return err
+		}
+		if err := s.blockchain.WriteBlocks(blocks); err != nil {
+			return err
+		}
+
+		s.statusLock.Lock()
+		s.status = pivotStatus.Copy()
+		s.statusLock.Unlock()
+	}
+
+	s.WatchSyncWithPeer(main, func(b *types.Block) bool {
+		return b.Header.Number >= pivotStatus.Number
+	})
+
+	return nil
+}
+
+// fetchSkeleton fetches every skeletonInterval-th header from main, starting
+// just after the local head and stopping pivotGap blocks behind main's head.
+func (s *Syncer) fetchSkeleton(main *SyncPeer) ([]*types.Header, error) {
+	status := main.Status()
+	if status == nil {
+		return nil, errNoMainSkeleton
+	}
+
+	stop := uint64(0)
+	if status.Number > pivotGap {
+		stop = status.Number - pivotGap
+	}
+
+	skeleton := []*types.Header{s.blockchain.Header()}
+
+	for n := skeleton[0].Number + skeletonInterval; n <= stop; n += skeletonInterval {
+		headers, err := getHeaders(main.Client(), n, n)
+		if err != nil || len(headers) == 0 {
+			return nil, errNoMainSkeleton
+		}
+		skeleton = append(skeleton, headers[0])
+	}
+
+	if last := skeleton[len(skeleton)-1]; last.Number != stop && stop > last.Number {
+		headers, err := getHeaders(main.Client(), stop, stop)
+		if err != nil || len(headers) == 0 {
+			return nil, errNoMainSkeleton
+		}
+		skeleton = append(skeleton, headers[0])
+	}
+
+	return skeleton, nil
+}
+
+// runSkeletonTasks fans sub-tasks for every gap between two skeleton anchors
+// out to a worker pool drawing from every connected peer, re-queueing any
+// sub-task whose peer misbehaves or disappears until it succeeds elsewhere.
+// Workers are stopped via stopCh, not by closing tasks: a worker can be in
+// the middle of re-queueing a failed sub-task when the last other sub-task
+// completes, and closing tasks out from under that send would panic.
+func (s *Syncer) runSkeletonTasks(skeleton []*types.Header) ([]*skeletonResult, error) {
+	numTasks := len(skeleton) - 1
+
+	tasks := make(chan *skeletonTask, numTasks)
+	for i := 0; i < numTasks; i++ {
+		tasks <- &skeletonTask{index: i, from: skeleton[i], to: skeleton[i+1]}
+	}
+
+	resultCh := make(chan *skeletonResult, numTasks)
+	errCh := make(chan error, 1)
+	stopCh := make(chan struct{})
+
+	var wg sync.WaitGroup
+	for i := 0; i < numSkeletonWorkers; i++ {
+		wg.Add(1)
+		go s.runSkeletonWorker(&wg, tasks, resultCh, errCh, stopCh)
+	}
+
+	results := make([]*skeletonResult, numTasks)
+	received := 0
+	var taskErr error
+
+	for received < numTasks && taskErr == nil {
+		select {
+		case res := <-resultCh:
+			results[res.index] = res
+			received++
+		case err := <-errCh:
+			taskErr = err
+		}
+	}
+
+	// every sub-task has either succeeded or a worker has given up outright;
+	// tell the rest to stop and wait for them to actually exit before
+	// returning, so no worker outlives this call.
+	close(stopCh)
+	wg.Wait()
+
+	if taskErr != nil {
+		return nil, taskErr
+	}
+	return results, nil
+}
+
+func (s *Syncer) runSkeletonWorker(wg *sync.WaitGroup, tasks chan *skeletonTask, results chan *skeletonResult, errCh chan error, stopCh chan struct{}) {
+	defer wg.Done()
+
+	for {
+		var task *skeletonTask
+		select {
+		case task = <-tasks:
+		case <-stopCh:
+			return
+		}
+
+		peer := s.pickSubTaskPeer(task)
+		if peer == nil {
+			select {
+			case errCh <- errNoSyncPeer:
+			default:
+			}
+			return
+		}
+
+		blocks, err := s.fetchSubTask(peer, task)
+		if err != nil {
+			s.logger.Warn("skeleton sub-task failed, re-queueing to another peer",
+				"peer", peer.ID(), "from", task.from.Number, "to", task.to.Number, "err", err)
+			s.markBad(peer.ID(), err.Error())
+			select {
+			case tasks <- task:
+			case <-stopCh:
+				return
+			}
+			continue
+		}
+
+		select {
+		case results <- &skeletonResult{index: task.index, blocks: blocks}:
+		case <-stopCh:
+			return
+		}
+	}
+}
+
+// pickSubTaskPeer returns the most-trusted peer tall enough to serve task's
+// entire range, so a sub-task is never handed to a peer too short to hold it
+// (guaranteed markBad-and-requeue churn) or to a peer already known to lie.
+func (s *Syncer) pickSubTaskPeer(task *skeletonTask) *SyncPeer {
+	candidates := s.peerSet.AllAbove(task.to.Number)
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	best := candidates[0]
+	for _, p := range candidates[1:] {
+		if p.Trust() > best.Trust() {
+			best = p
+		}
+	}
+	return best
+}
+
+// fetchSubTask downloads every block strictly between task.from and task.to
+// (inclusive of task.to) from peer and verifies they hash-chain to both anchors.
+func (s *Syncer) fetchSubTask(peer *SyncPeer, task *skeletonTask) ([]*types.Block, error) {
+	blocks, err := getBlocks(peer.Client(), task.from.Number+1, task.to.Number)
+	if err != nil {
+		return nil, err
+	}
+	if uint64(len(blocks)) != task.to.Number-task.from.Number {
+		return nil, errors.New("peer returned an incomplete block range")
+	}
+
+	parentHash := task.from.Hash
+	for _, b := range blocks {
+		if b.Header.ParentHash != parentHash {
+			return nil, errors.New("sub-task blocks do not hash-chain to the skeleton anchor")
+		}
+		parentHash = b.Header.Hash
+	}
+	if parentHash != task.to.Hash {
+		return nil, errors.New("sub-task blocks do not reach the skeleton's end anchor")
+	}
+
+	return blocks, nil
+}
@@ -0,0 +1,97 @@
+package protocol
+
+import (
+	"testing"
+	"time"
+
+	"github.com/0xPolygon/polygon-sdk/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// tamperedChain wraps a blockchainShim and flips the hash of every header
+// strictly after tamperFrom, simulating a peer that lies about part of its chain.
+type tamperedChain struct {
+	blockchainShim
+	tamperFrom uint64
+}
+
+func (t *tamperedChain) GetHeaderByNumber(n uint64) (*types.Header, bool) {
+	h, ok := t.blockchainShim.GetHeaderByNumber(n)
+	if !ok || n <= t.tamperFrom {
+		return h, ok
+	}
+	bad := *h
+	bad.ParentHash = types.Hash{0xff}
+	bad.ComputeHash()
+	return &bad, true
+}
+
+func (t *tamperedChain) GetBlockByNumber(n uint64, full bool) (*types.Block, bool) {
+	b, ok := t.blockchainShim.GetBlockByNumber(n, full)
+	if !ok || n <= t.tamperFrom {
+		return b, ok
+	}
+	bad := *b.Header
+	bad.ParentHash = types.Hash{0xff}
+	bad.ComputeHash()
+	return &types.Block{Header: &bad}, true
+}
+
+func TestFastSyncWithPeers_MultiPeer(t *testing.T) {
+	chain := NewRandomChain(t, 10)
+	peerChains := []blockchainShim{
+		NewRandomChain(t, 500),
+		NewRandomChain(t, 500),
+		NewRandomChain(t, 500),
+	}
+
+	syncer, _ := SetupSyncerNetwork(t, chain, peerChains)
+
+	err := syncer.FastSyncWithPeers()
+	assert.NoError(t, err)
+
+	// FastSyncWithPeers must actually reach the peer's head: the skeleton
+	// alone stops pivotGap blocks short, and without backfilling that range
+	// directly the node would be stuck there forever, since WatchSyncWithPeer
+	// only drains already-gossiped blocks and can't fetch a historical gap.
+	assert.Equal(t, uint64(499), syncer.blockchain.Header().Number)
+}
+
+func TestFastSyncWithPeers_LyingPeer(t *testing.T) {
+	chain := NewRandomChain(t, 10)
+	honest := NewRandomChain(t, 500)
+	lying := &tamperedChain{blockchainShim: NewRandomChain(t, 500), tamperFrom: 200}
+
+	syncer, _ := SetupSyncerNetwork(t, chain, []blockchainShim{honest, lying})
+
+	// the lying peer's sub-tasks should be detected and re-queued onto the
+	// honest peer rather than corrupting the local chain
+	err := syncer.FastSyncWithPeers()
+	assert.NoError(t, err)
+}
+
+func TestFastSyncWithPeers_PeerDisconnectsMidTask(t *testing.T) {
+	chain := NewRandomChain(t, 10)
+	peerChains := []blockchainShim{
+		NewRandomChain(t, 500),
+		NewRandomChain(t, 500),
+	}
+
+	syncer, peerSyncers := SetupSyncerNetwork(t, chain, peerChains)
+
+	doneCh := make(chan error, 1)
+	go func() {
+		doneCh <- syncer.FastSyncWithPeers()
+	}()
+
+	// disconnect one peer shortly after the sync starts, mid sub-task download
+	time.Sleep(10 * time.Millisecond)
+	peerSyncers[0].server.Disconnect(syncer.server.AddrInfo().ID, "bye")
+
+	select {
+	case err := <-doneCh:
+		assert.NoError(t, err)
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out waiting for FastSyncWithPeers to recover from a mid-task disconnect")
+	}
+}
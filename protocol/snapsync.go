@@ -0,0 +1,100 @@
+package protocol
+
+import (
+	"fmt"
+
+	"github.com/0xPolygon/polygon-sdk/types"
+)
+
+// snapPivotGap is how many blocks behind the peer's head FastSync's pivot
+// block sits; blocks after the pivot are still replayed through the EVM.
+const snapPivotGap = 64
+
+// FastSync catches a fresh node up to peer by downloading receipts and bodies
+// instead of re-executing every historical transaction. Only the last
+// snapPivotGap blocks before the peer's head are replayed with the EVM.
+func (s *Syncer) FastSync(peer *SyncPeer) error {
+	status := peer.Status()
+	if status == nil {
+		return errNoSyncPeer
+	}
+
+	local := s.blockchain.Header()
+	if status.Number <= local.Number {
+		return nil
+	}
+
+	pivot := uint64(0)
+	if status.Number > snapPivotGap {
+		pivot = status.Number - snapPivotGap
+	}
+	if pivot < local.Number {
+		pivot = local.Number
+	}
+
+	headers, err := getHeaders(peer.Client(), local.Number+1, status.Number)
+	if err != nil {
+		return err
+	}
+
+	if pivot > local.Number {
+		if err := s.fastSyncReceipts(peer, headers, local.Number+1, pivot); err != nil {
+			return err
+		}
+	}
+
+	if pivot < status.Number {
+		blocks, err := getBlocks(peer.Client(), pivot+1, status.Number)
+		if err != nil {
+			return err
+		}
+		if err := s.blockchain.WriteBlocks(blocks); err != nil {
+			return err
+		}
+	}
+
+	s.statusLock.Lock()
+	s.status = status.Copy()
+	s.statusLock.Unlock()
+
+	return nil
+}
+
+// fastSyncReceipts downloads bodies and receipts for [from, to], verifies each
+// block's receipts against its header's ReceiptsRoot, and writes them without
+// replaying the transactions through the EVM.
+func (s *Syncer) fastSyncReceipts(peer *SyncPeer, headers []*types.Header, from, to uint64) error {
+	bodies, err := getBlockBodies(peer.Client(), from, to)
+	if err != nil {
+		return err
+	}
+	receipts, err := getReceipts(peer.Client(), from, to)
+	if err != nil {
+		return err
+	}
+	if len(bodies) != len(receipts) {
+		return fmt.Errorf("peer returned %d bodies but %d receipt sets", len(bodies), len(receipts))
+	}
+
+	blocks := make([]*types.Block, 0, len(bodies))
+	for i, h := range headers {
+		if h.Number > to {
+			break
+		}
+		if i >= len(bodies) {
+			return fmt.Errorf("peer did not return a body for block %d", h.Number)
+		}
+
+		root := types.DeriveReceiptsRoot(receipts[i])
+		if root != h.ReceiptsRoot {
+			return fmt.Errorf("receipts root mismatch for block %d: got %s, want %s", h.Number, root, h.ReceiptsRoot)
+		}
+
+		blocks = append(blocks, &types.Block{
+			Header:       h,
+			Transactions: bodies[i].Transactions,
+		})
+	}
+
+	return s.blockchain.WriteBlocksAndReceipts(blocks, receipts)
+}
@@ -0,0 +1,82 @@
+package protocol
+
+import (
+	"testing"
+	"time"
+
+	"github.com/0xPolygon/polygon-sdk/blockchain"
+	"github.com/0xPolygon/polygon-sdk/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// receiptsStub serves a single fake receipt per block, so FastSync has
+// something to verify the ReceiptsRoot against.
+type receiptsStub struct {
+	blockchainShim
+}
+
+func (r *receiptsStub) GetReceiptsByHash(hash types.Hash) ([]*types.Receipt, error) {
+	return []*types.Receipt{{TxHash: hash}}, nil
+}
+
+func TestFastSync(t *testing.T) {
+	// snapPivotGap+10 keeps the pivot comfortably above the local head, so the
+	// receipts path (fastSyncReceipts) actually runs instead of collapsing to
+	// a plain getBlocks replay of the whole peer chain.
+	headers := blockchain.NewTestHeaderChainWithSeed(nil, snapPivotGap+10, 0)
+	for _, h := range headers {
+		receipts := []*types.Receipt{{TxHash: h.Hash}}
+		h.ReceiptsRoot = types.DeriveReceiptsRoot(receipts)
+	}
+
+	chain := NewMockBlockchain(blockchain.NewTestHeaderChainWithSeed(nil, 1, 0))
+	peerChain := &receiptsStub{NewMockBlockchain(headers)}
+
+	syncer, peerSyncers := SetupSyncerNetwork(t, chain, []blockchainShim{peerChain})
+	peer := getPeer(syncer, peerSyncers[0].server.AddrInfo().ID)
+	assert.NotNil(t, peer)
+
+	err := syncer.FastSync(peer)
+	assert.NoError(t, err)
+	WaitUntilProcessedAllEvents(t, syncer, 10*time.Second)
+
+	expected := HeaderToStatus(headers[len(headers)-1])
+	assert.Equal(t, expected, syncer.status)
+
+	// the pivot sits snapPivotGap behind the peer's head, so everything below
+	// it must have come through fastSyncReceipts rather than getBlocks: its
+	// receipt mismatch check is the only place that would have rejected a
+	// wrong ReceiptsRoot before these blocks were written.
+	pivot := uint64(len(headers)-1) - snapPivotGap
+	assert.Greater(t, pivot, uint64(0))
+
+	for n := uint64(1); n <= pivot; n++ {
+		h, ok := syncer.blockchain.GetHeaderByNumber(n)
+		assert.True(t, ok)
+		assert.Equal(t, headers[n].ReceiptsRoot, h.ReceiptsRoot)
+	}
+}
+
+// TestFastSync_RejectsBadReceiptsRoot confirms fastSyncReceipts actually
+// verifies the receipts it downloads, rather than just writing whatever the
+// peer sends.
+func TestFastSync_RejectsBadReceiptsRoot(t *testing.T) {
+	headers := blockchain.NewTestHeaderChainWithSeed(nil, snapPivotGap+10, 0)
+	for _, h := range headers {
+		h.ReceiptsRoot = types.DeriveReceiptsRoot([]*types.Receipt{{TxHash: h.Hash}})
+	}
+
+	chain := NewMockBlockchain(blockchain.NewTestHeaderChainWithSeed(nil, 1, 0))
+	// receiptsStub always serves a receipt keyed on the header hash, which
+	// doesn't match the mismatched ReceiptsRoot set below.
+	peerChain := &receiptsStub{NewMockBlockchain(headers)}
+
+	syncer, peerSyncers := SetupSyncerNetwork(t, chain, []blockchainShim{peerChain})
+	peer := getPeer(syncer, peerSyncers[0].server.AddrInfo().ID)
+	assert.NotNil(t, peer)
+
+	headers[1].ReceiptsRoot = types.Hash{0x1}
+
+	err := syncer.FastSync(peer)
+	assert.Error(t, err)
+}
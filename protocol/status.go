@@ -0,0 +1,31 @@
+package protocol
+
+import (
+	"github.com/0xPolygon/polygon-sdk/protocol/peers"
+	"github.com/0xPolygon/polygon-sdk/types"
+)
+
+// Status is the status of the sync protocol, shared between the local node and its peers.
+//
+// It ranks chains by block number rather than total difficulty: for PoA/IBFT
+// deployments there is no meaningful mining difficulty, so height is what the
+// sync protocol actually cares about. CurrentTD/GetTD remain on blockchainShim
+// for JSON-RPC compatibility only and are no longer read on this hot path.
+type Status = peers.Status
+
+// HeaderToStatus converts a header to a Status
+func HeaderToStatus(h *types.Header) *Status {
+	return &Status{
+		Hash:   h.Hash,
+		Number: h.Number,
+	}
+}
+
+// GetCurrentStatus returns the status of the current chain head
+func GetCurrentStatus(b blockchainShim) *Status {
+	header := b.Header()
+	return &Status{
+		Hash:   header.Hash,
+		Number: header.Number,
+	}
+}
@@ -0,0 +1,554 @@
+package protocol
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/0xPolygon/polygon-sdk/blockchain"
+	"github.com/0xPolygon/polygon-sdk/network"
+	"github.com/0xPolygon/polygon-sdk/protocol/peers"
+	"github.com/0xPolygon/polygon-sdk/protocol/proto"
+	"github.com/0xPolygon/polygon-sdk/types"
+	"github.com/hashicorp/go-hclog"
+	libp2pPeer "github.com/libp2p/go-libp2p-core/peer"
+)
+
+const (
+	syncerV1 = "/syncer/0.1"
+
+	// maxConsecutiveTimeouts is how many idle polls WatchSyncWithPeer tolerates
+	// from a peer before treating it as having timed out
+	maxConsecutiveTimeouts = 3
+)
+
+var (
+	errNoPeers = errors.New("no peers connected")
+)
+
+// blockchainShim is the interface the syncer needs from the local blockchain,
+// kept narrow so it can be mocked out in tests.
+type blockchainShim interface {
+	Header() *types.Header
+	GetHeaderByNumber(n uint64) (*types.Header, bool)
+	GetHeaderByHash(hash types.Hash) (*types.Header, bool)
+	GetBlockByNumber(blockNumber uint64, full bool) (*types.Block, bool)
+	GetBodyByHash(hash types.Hash) (*types.Body, bool)
+	GetReceiptsByHash(hash types.Hash) ([]*types.Receipt, error)
+	SubscribeEvents() blockchain.Subscription
+	WriteBlocks(blocks []*types.Block) error
+	// WriteBlocksAndReceipts writes blocks fetched via FastSync together with
+	// their already-computed receipts, skipping EVM re-execution for them.
+	WriteBlocksAndReceipts(blocks []*types.Block, receipts [][]*types.Receipt) error
+	// CurrentTD and GetTD are kept for JSON-RPC compatibility (eth_getWork and
+	// friends still report difficulty); the sync hot path ranks peers by block
+	// number and peer trust score instead, since a PoA/IBFT chain has no
+	// meaningful difficulty to compare.
+	CurrentTD() *big.Int
+	GetTD(hash types.Hash) (*big.Int, bool)
+}
+
+// SyncPeer is the local view of a connected peer taking part in the sync
+// protocol. It now lives in protocol/peers so it, and the bookkeeping around
+// it, can be reused outside of Syncer.
+type SyncPeer = peers.Peer
+
+// Syncer is the sync protocol: it keeps track of connected peers' chain status,
+// and drives the local chain to catch up with the best of them.
+type Syncer struct {
+	logger     hclog.Logger
+	blockchain blockchainShim
+	server     *network.Server
+
+	peerSet peers.PeerSet
+
+	statusLock sync.Mutex
+	status     *Status
+
+	stopCh  chan struct{}
+	flushCh chan chan struct{}
+
+	// validatorVerifier and genesisValidators back WarpSyncWithPeer; both are
+	// nil until a consensus backend wires them in via the Set* methods.
+	validatorVerifier ValidatorSetVerifier
+	genesisValidators []types.Address
+}
+
+// NewSyncer creates a new sync protocol instance bound to the given server and blockchain
+func NewSyncer(logger hclog.Logger, server *network.Server, blockchain blockchainShim) *Syncer {
+	s := &Syncer{
+		logger:     logger.Named("syncer"),
+		blockchain: blockchain,
+		server:     server,
+		peerSet:    peers.NewMapPeerSet(),
+		status:     GetCurrentStatus(blockchain),
+		stopCh:     make(chan struct{}),
+		flushCh:    make(chan chan struct{}),
+	}
+	return s
+}
+
+// Start registers the syncer protocol and starts watching for peer and chain events
+func (s *Syncer) Start() {
+	s.server.RegisterProtocol(syncerV1, s)
+
+	go s.watchPeerEvents()
+	go s.watchBlockchainEvents()
+}
+
+// Close stops the syncer
+func (s *Syncer) Close() {
+	close(s.stopCh)
+}
+
+// Broadcast publishes a newly sealed block to the local chain head status
+// and announces it to connected peers
+func (s *Syncer) Broadcast(b *types.Block) {
+	s.statusLock.Lock()
+	s.status = HeaderToStatus(b.Header)
+	s.statusLock.Unlock()
+
+	s.peerSet.BroadcastExcept(b, "")
+}
+
+func (s *Syncer) watchBlockchainEvents() {
+	sub := s.blockchain.SubscribeEvents()
+	for {
+		evnt := sub.GetEventCh()
+		select {
+		case ev := <-evnt:
+			for _, b := range ev.NewChain {
+				s.Broadcast(b)
+			}
+		case req := <-s.flushCh:
+			close(req)
+		case <-s.stopCh:
+			return
+		}
+	}
+}
+
+func (s *Syncer) watchPeerEvents() {
+	updateCh, err := s.server.SubscribeCh()
+	if err != nil {
+		s.logger.Error("failed to subscribe to peer events", "err", err)
+		return
+	}
+
+	for {
+		select {
+		case evnt := <-updateCh:
+			switch evnt.Type {
+			case network.PeerEventConnected:
+				go s.handleNewPeer(evnt.PeerID)
+			case network.PeerEventDisconnected:
+				s.deletePeer(evnt.PeerID)
+			}
+		case <-s.stopCh:
+			return
+		}
+	}
+}
+
+func (s *Syncer) handleNewPeer(id libp2pPeer.ID) {
+	conn, err := s.server.NewProtoConnection(syncerV1, id)
+	if err != nil {
+		s.logger.Error("failed to open syncer stream", "id", id, "err", err)
+		return
+	}
+
+	peer := peers.NewPeer(id, conn)
+
+	status, err := peer.Client().GetCurrentStatus(context.Background(), &proto.Empty{})
+	if err != nil {
+		s.logger.Error("failed to read status from peer", "id", id, "err", err)
+		return
+	}
+	peer.SetStatus(statusFromProto(status))
+
+	s.peerSet.Add(id, peer)
+}
+
+func (s *Syncer) deletePeer(id libp2pPeer.ID) {
+	if peer, ok := s.peerSet.Remove(id); ok {
+		peer.Close()
+	}
+}
+
+// getPeer returns the tracked peer with the given id, or nil if not connected
+func getPeer(s *Syncer, id libp2pPeer.ID) *SyncPeer {
+	p, ok := s.peerSet.Get(id)
+	if !ok {
+		return nil
+	}
+	return p
+}
+
+// markBad penalizes id's trust score for reason, disconnecting it if that
+// drops it below peers.MinTrustScore, since its history of bad behaviour
+// makes it unsafe to keep syncing from.
+func (s *Syncer) markBad(id libp2pPeer.ID, reason string) {
+	if s.peerSet.MarkBad(id, reason) {
+		s.logger.Warn("dropping untrusted peer", "id", id, "reason", reason)
+		s.server.Disconnect(id, "bad sync history")
+	}
+}
+
+// markGood rewards id's trust score after a successful interaction, routed
+// through the peer set rather than the peer itself so implementations that
+// keep peers in a trust-ordered structure, like HeapPeerSet, can re-fix that
+// ordering.
+func (s *Syncer) markGood(id libp2pPeer.ID) {
+	s.peerSet.MarkGood(id)
+}
+
+// BestPeer returns the connected peer that is furthest ahead of the local
+// chain, breaking ties by trust score. Peers whose trust score has dropped
+// below peers.MinTrustScore are excluded, since their history of bad
+// behaviour makes their reported height unreliable; they are disconnected the
+// next time MarkBad observes them crossing that threshold. Returns nil if no
+// suitable peer exists.
+func (s *Syncer) BestPeer() *SyncPeer {
+	s.statusLock.Lock()
+	localNumber := s.status.Number
+	s.statusLock.Unlock()
+
+	return s.peerSet.Best(localNumber)
+}
+
+// findCommonAncestor performs a binary search over peer's headers to find the
+// latest header both chains agree on, returning that header and the first
+// header of peer's fork past it. Any failure on peer's side - a timeout fetching a
+// header, or peer failing to produce the fork header its own status implied
+// - is peer's fault, so it's penalized here rather than relying on every
+// caller to remember to do it.
+func (s *Syncer) findCommonAncestor(peer *SyncPeer, status *Status) (*types.Header, *types.Header, error) {
+	client := peer.Client()
+	h := s.blockchain.Header()
+
+	min := uint64(0)
+	max := h.Number
+	if status.Number < max {
+		max = status.Number
+	}
+
+	var header *types.Header
+	for min <= max {
+		mid := (min + max) / 2
+
+		localHeader, ok := s.blockchain.GetHeaderByNumber(mid)
+		if !ok {
+			return nil, nil, fmt.Errorf("local header %d not found", mid)
+		}
+
+		remoteHeaders, err := getHeaders(client, mid, mid)
+		if err != nil {
+			s.markBad(peer.ID(), err.Error())
+			return nil, nil, err
+		}
+		if len(remoteHeaders) == 0 {
+			max = mid - 1
+			continue
+		}
+		remoteHeader := remoteHeaders[0]
+
+		if localHeader.Hash == remoteHeader.Hash {
+			header = localHeader
+			min = mid + 1
+		} else {
+			if mid == 0 {
+				break
+			}
+			max = mid - 1
+		}
+	}
+
+	if header == nil {
+		s.markBad(peer.ID(), "fork not found")
+		return nil, nil, errors.New("fork not found")
+	}
+
+	forkHeaders, err := getHeaders(client, header.Number+1, header.Number+1)
+	if err != nil {
+		s.markBad(peer.ID(), err.Error())
+		return nil, nil, err
+	}
+	if len(forkHeaders) == 0 {
+		s.markBad(peer.ID(), "fork not found")
+		return nil, nil, errors.New("fork not found")
+	}
+
+	return header, forkHeaders[0], nil
+}
+
+// WatchSyncWithPeer streams blocks from peer as its head advances, writing them
+// to the local chain, until stopFn returns true for the received block.
+func (s *Syncer) WatchSyncWithPeer(peer *SyncPeer, stopFn func(b *types.Block) bool) {
+	consecutiveTimeouts := 0
+
+	for {
+		b, ok := peer.PopBlock(5 * time.Second)
+		if !ok {
+			consecutiveTimeouts++
+			if consecutiveTimeouts >= maxConsecutiveTimeouts {
+				s.markBad(peer.ID(), "timed out during WatchSyncWithPeer")
+				return
+			}
+			continue
+		}
+		consecutiveTimeouts = 0
+
+		if err := s.blockchain.WriteBlocks([]*types.Block{b}); err != nil {
+			s.logger.Error("failed to write block", "err", err)
+			s.markBad(peer.ID(), err.Error())
+			return
+		}
+
+		s.statusLock.Lock()
+		s.status = HeaderToStatus(b.Header)
+		s.statusLock.Unlock()
+
+		if stopFn(b) {
+			return
+		}
+	}
+}
+
+// BulkSyncWithPeer downloads every block between the local head and the peer's
+// head in one shot, and writes them to the local chain.
+func (s *Syncer) BulkSyncWithPeer(peer *SyncPeer) error {
+	status := peer.Status()
+
+	localHeader := s.blockchain.Header()
+	if status.Number == localHeader.Number {
+		return nil
+	}
+
+	// findCommonAncestor already penalizes peer on failure, so it isn't
+	// repeated here.
+	_, fork, err := s.findCommonAncestor(peer, status)
+	if err != nil {
+		return err
+	}
+
+	blocks, err := getBlocks(peer.Client(), fork.Number, status.Number)
+	if err != nil {
+		s.markBad(peer.ID(), err.Error())
+		return err
+	}
+
+	if err := s.blockchain.WriteBlocks(blocks); err != nil {
+		s.markBad(peer.ID(), err.Error())
+		return err
+	}
+
+	s.statusLock.Lock()
+	s.status = status.Copy()
+	s.statusLock.Unlock()
+
+	s.markGood(peer.ID())
+	return nil
+}
+
+func getHeaders(client proto.V1Client, from, to uint64) ([]*types.Header, error) {
+	resp, err := client.GetObjectHeaders(context.Background(), &proto.GetHeadersRequest{From: from, To: to})
+	if err != nil {
+		return nil, err
+	}
+
+	headers := make([]*types.Header, 0, len(resp.Objs))
+	for _, raw := range resp.Objs {
+		h := &types.Header{}
+		if err := h.UnmarshalRLP(raw); err != nil {
+			return nil, err
+		}
+		headers = append(headers, h)
+	}
+	return headers, nil
+}
+
+func getBlocks(client proto.V1Client, from, to uint64) ([]*types.Block, error) {
+	stream, err := client.GetBlocks(context.Background(), &proto.GetBlocksRequest{From: from, To: to})
+	if err != nil {
+		return nil, err
+	}
+
+	blocks := []*types.Block{}
+	for {
+		resp, err := stream.Recv()
+		if err != nil {
+			break
+		}
+		b := &types.Block{}
+		if err := b.UnmarshalRLP(resp.Data); err != nil {
+			return nil, err
+		}
+		blocks = append(blocks, b)
+	}
+	return blocks, nil
+}
+
+// GetCurrentStatus implements proto.V1Server: it returns the local chain head
+func (s *Syncer) GetCurrentStatus(ctx context.Context, _ *proto.Empty) (*proto.V1Status, error) {
+	s.statusLock.Lock()
+	status := s.status
+	s.statusLock.Unlock()
+
+	return &proto.V1Status{
+		Hash:   status.Hash.Bytes(),
+		Number: status.Number,
+	}, nil
+}
+
+// GetObjectHeaders implements proto.V1Server: it serves a range of local headers
+func (s *Syncer) GetObjectHeaders(ctx context.Context, req *proto.GetHeadersRequest) (*proto.Headers, error) {
+	resp := &proto.Headers{}
+	for n := req.From; n <= req.To; n++ {
+		h, ok := s.blockchain.GetHeaderByNumber(n)
+		if !ok {
+			break
+		}
+		raw, err := h.MarshalRLP()
+		if err != nil {
+			return nil, err
+		}
+		resp.Objs = append(resp.Objs, raw)
+	}
+	return resp, nil
+}
+
+// GetBlocks implements proto.V1Server: it streams a range of local blocks
+func (s *Syncer) GetBlocks(req *proto.GetBlocksRequest, stream proto.V1_GetBlocksServer) error {
+	for n := req.From; n <= req.To; n++ {
+		b, ok := s.blockchain.GetBlockByNumber(n, true)
+		if !ok {
+			break
+		}
+		raw, err := b.MarshalRLP()
+		if err != nil {
+			return err
+		}
+		if err := stream.Send(&proto.Block{Data: raw}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetBlockBodies implements proto.V1Server: it streams a range of local bodies
+func (s *Syncer) GetBlockBodies(req *proto.GetBlocksRequest, stream proto.V1_GetBlockBodiesServer) error {
+	for n := req.From; n <= req.To; n++ {
+		header, ok := s.blockchain.GetHeaderByNumber(n)
+		if !ok {
+			break
+		}
+		body, ok := s.blockchain.GetBodyByHash(header.Hash)
+		if !ok {
+			break
+		}
+		raw, err := body.MarshalRLP()
+		if err != nil {
+			return err
+		}
+		if err := stream.Send(&proto.Body{Data: raw}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetReceipts implements proto.V1Server: it streams a range of local receipts
+func (s *Syncer) GetReceipts(req *proto.GetBlocksRequest, stream proto.V1_GetReceiptsServer) error {
+	for n := req.From; n <= req.To; n++ {
+		header, ok := s.blockchain.GetHeaderByNumber(n)
+		if !ok {
+			break
+		}
+		receipts, err := s.blockchain.GetReceiptsByHash(header.Hash)
+		if err != nil {
+			return err
+		}
+		raw, err := types.Receipts(receipts).MarshalRLP()
+		if err != nil {
+			return err
+		}
+		if err := stream.Send(&proto.Receipts{Data: raw}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Watch implements proto.V1Server: it streams local status updates as the chain grows
+func (s *Syncer) Watch(_ *proto.Empty, stream proto.V1_WatchServer) error {
+	sub := s.blockchain.SubscribeEvents()
+	for {
+		evnt := sub.GetEventCh()
+		select {
+		case ev := <-evnt:
+			for _, b := range ev.NewChain {
+				status := HeaderToStatus(b.Header)
+				if err := stream.Send(&proto.V1Status{
+					Hash:   status.Hash.Bytes(),
+					Number: status.Number,
+				}); err != nil {
+					return err
+				}
+			}
+		case <-s.stopCh:
+			return nil
+		}
+	}
+}
+
+func getBlockBodies(client proto.V1Client, from, to uint64) ([]*types.Body, error) {
+	stream, err := client.GetBlockBodies(context.Background(), &proto.GetBlocksRequest{From: from, To: to})
+	if err != nil {
+		return nil, err
+	}
+
+	bodies := []*types.Body{}
+	for {
+		resp, err := stream.Recv()
+		if err != nil {
+			break
+		}
+		body := &types.Body{}
+		if err := body.UnmarshalRLP(resp.Data); err != nil {
+			return nil, err
+		}
+		bodies = append(bodies, body)
+	}
+	return bodies, nil
+}
+
+func getReceipts(client proto.V1Client, from, to uint64) ([][]*types.Receipt, error) {
+	stream, err := client.GetReceipts(context.Background(), &proto.GetBlocksRequest{From: from, To: to})
+	if err != nil {
+		return nil, err
+	}
+
+	receipts := [][]*types.Receipt{}
+	for {
+		resp, err := stream.Recv()
+		if err != nil {
+			break
+		}
+		var rs types.Receipts
+		if err := rs.UnmarshalRLP(resp.Data); err != nil {
+			return nil, err
+		}
+		receipts = append(receipts, rs)
+	}
+	return receipts, nil
+}
+
+func statusFromProto(p *proto.V1Status) *Status {
+	return &Status{
+		Hash:   types.BytesToHash(p.Hash),
+		Number: p.Number,
+	}
+}
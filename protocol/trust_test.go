@@ -0,0 +1,74 @@
+package protocol
+
+import (
+	"testing"
+
+	"github.com/0xPolygon/polygon-sdk/blockchain"
+	"github.com/0xPolygon/polygon-sdk/protocol/peers"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBestPeer_IgnoresUntrustedPeerWithEqualHeight(t *testing.T) {
+	chain := NewRandomChain(t, 10)
+	peerChains := []blockchainShim{
+		NewRandomChain(t, 100),
+		NewRandomChain(t, 100),
+	}
+
+	syncer, peerSyncers := SetupSyncerNetwork(t, chain, peerChains)
+
+	badPeer := getPeer(syncer, peerSyncers[1].server.AddrInfo().ID)
+	assert.NotNil(t, badPeer)
+	for i := 0; i <= -peers.MinTrustScore; i++ {
+		badPeer.PenalizeTrust()
+	}
+
+	best := syncer.BestPeer()
+	assert.NotNil(t, best)
+	assert.Equal(t, peerSyncers[0].server.AddrInfo().ID.String(), best.ID().String())
+}
+
+func TestBestPeer_DemotesPeerThatLiedDuringBulkSync(t *testing.T) {
+	chain := NewRandomChain(t, 10)
+
+	// honest peer's chain shares chain's genesis-seeded prefix, so BulkSync
+	// finds a real common ancestor with it.
+	honestHeaders := blockchain.NewTestHeaderChainWithSeed(nil, 50, 0)
+
+	// the liar's chain is taller but diverges from genesis itself, so it
+	// shares no common ancestor with the local chain at all - a lie a real
+	// bulk sync has to catch on its own, not one asserted by calling
+	// PenalizeTrust directly.
+	liarHeaders := blockchain.NewTestHeaderChainWithSeed(nil, 500, 0)
+	liarHeaders[0].Difficulty++
+	liarHeaders[0].ComputeHash()
+	for i := 1; i < len(liarHeaders); i++ {
+		liarHeaders[i].ParentHash = liarHeaders[i-1].Hash
+		liarHeaders[i].ComputeHash()
+	}
+
+	peerChains := []blockchainShim{
+		NewMockBlockchain(honestHeaders),
+		NewMockBlockchain(liarHeaders),
+	}
+
+	syncer, peerSyncers := SetupSyncerNetwork(t, chain, peerChains)
+
+	liar := getPeer(syncer, peerSyncers[1].server.AddrInfo().ID)
+	assert.NotNil(t, liar)
+
+	// before being caught, the taller peer outranks the shorter honest one
+	assert.Equal(t, peerSyncers[1].server.AddrInfo().ID.String(), syncer.BestPeer().ID().String())
+
+	// repeated BulkSyncWithPeer calls against a peer with no common ancestor
+	// drive real findCommonAncestor failures, which now markBad the peer
+	// directly instead of a test mutating its trust score by hand.
+	for i := 0; i <= -peers.MinTrustScore; i++ {
+		err := syncer.BulkSyncWithPeer(liar)
+		assert.Error(t, err)
+	}
+
+	best := syncer.BestPeer()
+	assert.NotNil(t, best)
+	assert.Equal(t, peerSyncers[0].server.AddrInfo().ID.String(), best.ID().String())
+}
@@ -0,0 +1,312 @@
+package protocol
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/0xPolygon/polygon-sdk/protocol/proto"
+	"github.com/0xPolygon/polygon-sdk/types"
+	"github.com/umbracle/fastrlp"
+)
+
+// warpSyncMaxResponseSize caps the total size of a single GetWarpProof
+// response. GetWarpProof enforces it server-side, breaking a long chain of
+// fragments into a handful of round-trips instead of one unbounded response;
+// WarpSyncWithPeer also checks it against whatever a peer actually sends,
+// rejecting an over-cap response unless it holds a single fragment, so a peer
+// that doesn't honor the cap can't force a node to buffer an unbounded amount
+// of headers. A var, not a const, so tests can shrink it to exercise
+// pagination without constructing a chain that actually exceeds 16 MiB.
+var warpSyncMaxResponseSize = 16 * 1024 * 1024
+
+var (
+	errNoValidatorVerifier = errors.New("warp sync: no validator set verifier configured")
+	errEmptyWarpProof      = errors.New("warp sync: peer returned an empty proof")
+	errInvalidWarpFragment = errors.New("warp sync: fragment carries no headers")
+	errWarpProofTooLarge   = errors.New("warp sync: proof exceeds warpSyncMaxResponseSize")
+)
+
+// WarpFragment is a range of headers plus the finality justification (the IBFT
+// committed seals) authorizing the validator-set change on its last header.
+type WarpFragment struct {
+	Headers []*types.Header
+	Seals   [][]byte
+}
+
+// Marshal RLP-encodes the fragment as [headers, seals], matching the RLP
+// encoding used for every other object this protocol transmits. Each header
+// is carried as its own pre-encoded MarshalRLP blob rather than re-derived
+// field-by-field, so decoding reuses types.Header's own UnmarshalRLP.
+func (f *WarpFragment) Marshal() ([]byte, error) {
+	ar := &fastrlp.Arena{}
+
+	headerList := ar.NewArray()
+	for _, h := range f.Headers {
+		raw, err := h.MarshalRLP()
+		if err != nil {
+			return nil, err
+		}
+		headerList.Set(ar.NewBytes(raw))
+	}
+
+	sealList := ar.NewArray()
+	for _, seal := range f.Seals {
+		sealList.Set(ar.NewBytes(seal))
+	}
+
+	vv := ar.NewArray()
+	vv.Set(headerList)
+	vv.Set(sealList)
+
+	return vv.MarshalTo(nil), nil
+}
+
+// Unmarshal decodes a fragment previously produced by Marshal.
+func (f *WarpFragment) Unmarshal(data []byte) error {
+	p := &fastrlp.Parser{}
+	v, err := p.Parse(data)
+	if err != nil {
+		return err
+	}
+
+	elems, err := v.GetElems()
+	if err != nil {
+		return err
+	}
+	if len(elems) != 2 {
+		return fmt.Errorf("warp fragment: expected 2 RLP elements, found %d", len(elems))
+	}
+
+	headerElems, err := elems[0].GetElems()
+	if err != nil {
+		return err
+	}
+	f.Headers = make([]*types.Header, len(headerElems))
+	for i, he := range headerElems {
+		raw, err := he.Bytes()
+		if err != nil {
+			return err
+		}
+		h := &types.Header{}
+		if err := h.UnmarshalRLP(raw); err != nil {
+			return err
+		}
+		f.Headers[i] = h
+	}
+
+	sealElems, err := elems[1].GetElems()
+	if err != nil {
+		return err
+	}
+	f.Seals = make([][]byte, len(sealElems))
+	for i, se := range sealElems {
+		raw, err := se.Bytes()
+		if err != nil {
+			return err
+		}
+		f.Seals[i] = append([]byte(nil), raw...)
+	}
+
+	return nil
+}
+
+// ValidatorSetVerifier recomputes the validator set authorized by a finality
+// proof, so WarpSync stays agnostic to the consensus engine producing it.
+type ValidatorSetVerifier interface {
+	// VerifyFinality checks that seals were signed by at least 2/3 of
+	// validators, and returns the validator set in force after header.
+	VerifyFinality(header *types.Header, validators []types.Address, seals [][]byte) ([]types.Address, error)
+	// ExtractSeals returns the committed seals already embedded in header
+	// (e.g. in its IBFT extra data) that justify its validator-set change, so
+	// GetWarpProof can serve a proof a peer can actually verify.
+	ExtractSeals(header *types.Header) ([][]byte, error)
+}
+
+// SetValidatorSetVerifier wires the consensus-specific finality checker used
+// by WarpSyncWithPeer and GetWarpProof. It must be called, together with
+// SetGenesisValidators, before either is used.
+func (s *Syncer) SetValidatorSetVerifier(v ValidatorSetVerifier) {
+	s.validatorVerifier = v
+}
+
+// SetGenesisValidators sets the validator set trusted at the chain's genesis,
+// the root of trust every warp proof's finality chain is verified against.
+func (s *Syncer) SetGenesisValidators(validators []types.Address) {
+	s.genesisValidators = validators
+}
+
+// WarpSyncWithPeer verifies a chain of finality fragments from peer, starting
+// at trustedGenesisHash, writes the verified blocks up to the last fragment's
+// header into the local chain, and hands off to BulkSyncWithPeer to fetch
+// whatever of the peer's head still lies beyond it. GetWarpProof bounds each
+// of its responses to warpSyncMaxResponseSize, so this fetches the fragments
+// across as many round-trips as it takes, resuming each one from the last
+// verified header via startHash.
+func (s *Syncer) WarpSyncWithPeer(peer *SyncPeer, trustedGenesisHash types.Hash) error {
+	if s.validatorVerifier == nil {
+		return errNoValidatorVerifier
+	}
+
+	startHeader, ok := s.blockchain.GetHeaderByHash(trustedGenesisHash)
+	if !ok {
+		return fmt.Errorf("warp sync: trusted genesis hash %s not found locally", trustedGenesisHash)
+	}
+
+	validators := s.genesisValidators
+	prevHash := trustedGenesisHash
+	var verifiedHeaders []*types.Header
+
+	for round := 0; ; round++ {
+		resp, err := peer.Client().GetWarpProof(context.Background(), &proto.WarpProofRequest{StartHash: prevHash.Bytes()})
+		if err != nil {
+			return err
+		}
+		if len(resp.Fragments) == 0 {
+			if round == 0 {
+				return errEmptyWarpProof
+			}
+			// no more fragments past prevHash: the proof is exhausted and
+			// every header through the peer's finalized head is verified.
+			break
+		}
+
+		// GetWarpProof caps every response to warpSyncMaxResponseSize itself,
+		// except that it always sends at least one fragment; check it here too
+		// so a peer that doesn't honor the cap can't force this node to buffer
+		// an unbounded multi-fragment response anyway.
+		size := 0
+		for _, raw := range resp.Fragments {
+			size += len(raw)
+		}
+		if size > warpSyncMaxResponseSize && len(resp.Fragments) > 1 {
+			return errWarpProofTooLarge
+		}
+
+		for i, raw := range resp.Fragments {
+			fragment := &WarpFragment{}
+			if err := fragment.Unmarshal(raw); err != nil {
+				return fmt.Errorf("warp fragment %d: %w", i, err)
+			}
+			if len(fragment.Headers) == 0 {
+				return errInvalidWarpFragment
+			}
+
+			// (a) hash-linkage across every header in the fragment, continuing on
+			// from the previous fragment's last verified header. Hashes are
+			// recomputed rather than trusted off the wire, so a peer can't forge
+			// a self-consistent chain of headers that never hash to what they claim.
+			for _, h := range fragment.Headers {
+				h.ComputeHash()
+				if h.ParentHash != prevHash {
+					return fmt.Errorf("warp fragment %d does not hash-link to %s", i, prevHash)
+				}
+				prevHash = h.Hash
+			}
+
+			last := fragment.Headers[len(fragment.Headers)-1]
+
+			// (b) recompute the validator set from the previous fragment and check
+			// the seals on this fragment come from >= 2/3 of it
+			newValidators, err := s.validatorVerifier.VerifyFinality(last, validators, fragment.Seals)
+			if err != nil {
+				return fmt.Errorf("warp fragment %d: %w", i, err)
+			}
+
+			validators = newValidators
+			verifiedHeaders = append(verifiedHeaders, fragment.Headers...)
+		}
+	}
+
+	lastHeader := verifiedHeaders[len(verifiedHeaders)-1]
+
+	blocks, err := getBlocks(peer.Client(), startHeader.Number+1, lastHeader.Number)
+	if err != nil {
+		return err
+	}
+	if len(blocks) != len(verifiedHeaders) {
+		return errors.New("warp sync: peer returned an incomplete block range for the verified headers")
+	}
+	for i, b := range blocks {
+		if b.Header.Hash != verifiedHeaders[i].Hash {
+			return fmt.Errorf("warp sync: block %d does not match its verified header", b.Header.Number)
+		}
+	}
+
+	if err := s.blockchain.WriteBlocks(blocks); err != nil {
+		return err
+	}
+
+	s.statusLock.Lock()
+	s.status = HeaderToStatus(lastHeader)
+	s.statusLock.Unlock()
+
+	// the local chain now ends at lastHeader, so this only fetches whatever of
+	// the peer's head still lies beyond the warp proof.
+	return s.BulkSyncWithPeer(peer)
+}
+
+// GetWarpProof implements proto.V1Server: it chunks local headers after
+// startHash into skeletonInterval-sized fragments, each carrying the
+// committed seals that justify its last header's validator-set change, and
+// stops once the response reaches warpSyncMaxResponseSize. A caller wanting
+// the rest of the chain calls again with startHash set to the last fragment's
+// last header, resuming where this response left off; WarpSyncWithPeer does
+// exactly that, so a chain far longer than one response can hold is still
+// served, just across more round-trips.
+func (s *Syncer) GetWarpProof(ctx context.Context, req *proto.WarpProofRequest) (*proto.WarpProof, error) {
+	if s.validatorVerifier == nil {
+		return nil, errNoValidatorVerifier
+	}
+
+	startHeader, ok := s.blockchain.GetHeaderByHash(types.BytesToHash(req.StartHash))
+	if !ok {
+		return nil, errors.New("warp sync: unknown start hash")
+	}
+
+	head := s.blockchain.Header()
+	proofResp := &proto.WarpProof{}
+	size := 0
+
+	for from := startHeader.Number + 1; from <= head.Number; {
+		to := from + skeletonInterval - 1
+		if to > head.Number {
+			to = head.Number
+		}
+
+		headers := make([]*types.Header, 0, to-from+1)
+		for n := from; n <= to; n++ {
+			h, ok := s.blockchain.GetHeaderByNumber(n)
+			if !ok {
+				return nil, fmt.Errorf("warp sync: missing header %d", n)
+			}
+			headers = append(headers, h)
+		}
+
+		last := headers[len(headers)-1]
+		seals, err := s.validatorVerifier.ExtractSeals(last)
+		if err != nil {
+			return nil, fmt.Errorf("warp sync: extracting seals for header %d: %w", last.Number, err)
+		}
+
+		raw, err := (&WarpFragment{Headers: headers, Seals: seals}).Marshal()
+		if err != nil {
+			return nil, err
+		}
+
+		// stop this response, not the whole proof, once it reaches the cap -
+		// the caller resumes from here on its next round-trip. Always include
+		// at least one fragment so a single fragment larger than the cap
+		// can't stall the handshake forever.
+		if size+len(raw) > warpSyncMaxResponseSize && len(proofResp.Fragments) > 0 {
+			break
+		}
+
+		proofResp.Fragments = append(proofResp.Fragments, raw)
+		size += len(raw)
+
+		from = to + 1
+	}
+
+	return proofResp, nil
+}
@@ -0,0 +1,125 @@
+package protocol
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/0xPolygon/polygon-sdk/blockchain"
+	"github.com/0xPolygon/polygon-sdk/protocol/proto"
+	"github.com/0xPolygon/polygon-sdk/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// permissiveVerifier accepts every fragment and rotates the validator set by
+// appending the fragment's last header hash, simulating validator-set churn.
+type permissiveVerifier struct{}
+
+func (permissiveVerifier) VerifyFinality(header *types.Header, validators []types.Address, seals [][]byte) ([]types.Address, error) {
+	return append(validators, types.BytesToAddress(header.Hash.Bytes())), nil
+}
+
+// ExtractSeals stubs a single placeholder seal per header, since the mock
+// chain carries no real IBFT extra data for permissiveVerifier to parse.
+func (permissiveVerifier) ExtractSeals(header *types.Header) ([][]byte, error) {
+	return [][]byte{header.Hash.Bytes()}, nil
+}
+
+// rejectingVerifier simulates a fragment whose seals don't reach quorum
+type rejectingVerifier struct{}
+
+func (rejectingVerifier) VerifyFinality(header *types.Header, validators []types.Address, seals [][]byte) ([]types.Address, error) {
+	return nil, errors.New("insufficient quorum")
+}
+
+func (rejectingVerifier) ExtractSeals(header *types.Header) ([][]byte, error) {
+	return [][]byte{header.Hash.Bytes()}, nil
+}
+
+func TestWarpSyncWithPeer_FastForwardsToFinalizedHead(t *testing.T) {
+	headers := blockchain.NewTestHeaderChainWithSeed(nil, 1000, 0)
+	genesis := headers[0]
+
+	chain := NewMockBlockchain([]*types.Header{genesis})
+	peerChain := NewMockBlockchain(headers)
+
+	syncer, peerSyncers := SetupSyncerNetwork(t, chain, []blockchainShim{peerChain})
+	syncer.SetValidatorSetVerifier(permissiveVerifier{})
+	syncer.SetGenesisValidators([]types.Address{})
+	peerSyncers[0].SetValidatorSetVerifier(permissiveVerifier{})
+
+	peer := getPeer(syncer, peerSyncers[0].server.AddrInfo().ID)
+	assert.NotNil(t, peer)
+
+	err := syncer.WarpSyncWithPeer(peer, genesis.Hash)
+	assert.NoError(t, err)
+
+	expected := HeaderToStatus(headers[len(headers)-1])
+	assert.Equal(t, expected, syncer.status)
+
+	// the local chain itself must have advanced, not just the in-memory
+	// status - otherwise a later BulkSyncWithPeer would start from genesis.
+	assert.Equal(t, headers[len(headers)-1].Number, syncer.blockchain.Header().Number)
+}
+
+// TestWarpSyncWithPeer_ManyRoundTripsAcrossValidatorRotations stands in for
+// the real scenario GetWarpProof's pagination exists for - a chain far too
+// long to fit in one 16 MiB response, with many validator-set rotations
+// along the way. warpSyncMaxResponseSize is shrunk to force one fragment per
+// response, so a 5000-header chain already drives the same dozens-of-
+// round-trips path a 100k-header chain would, without the test itself
+// needing to build one.
+func TestWarpSyncWithPeer_ManyRoundTripsAcrossValidatorRotations(t *testing.T) {
+	originalMax := warpSyncMaxResponseSize
+	warpSyncMaxResponseSize = 1
+	defer func() { warpSyncMaxResponseSize = originalMax }()
+
+	const numHeaders = 5000
+	headers := blockchain.NewTestHeaderChainWithSeed(nil, numHeaders, 0)
+	genesis := headers[0]
+
+	chain := NewMockBlockchain([]*types.Header{genesis})
+	peerChain := NewMockBlockchain(headers)
+
+	syncer, peerSyncers := SetupSyncerNetwork(t, chain, []blockchainShim{peerChain})
+	syncer.SetValidatorSetVerifier(permissiveVerifier{})
+	syncer.SetGenesisValidators([]types.Address{})
+	peerSyncers[0].SetValidatorSetVerifier(permissiveVerifier{})
+
+	peer := getPeer(syncer, peerSyncers[0].server.AddrInfo().ID)
+	assert.NotNil(t, peer)
+
+	// this test only proves pagination works if pagination actually kicks in
+	resp, err := peer.Client().GetWarpProof(context.Background(), &proto.WarpProofRequest{StartHash: genesis.Hash.Bytes()})
+	assert.NoError(t, err)
+	assert.Len(t, resp.Fragments, 1)
+
+	err = syncer.WarpSyncWithPeer(peer, genesis.Hash)
+	assert.NoError(t, err)
+
+	expected := HeaderToStatus(headers[len(headers)-1])
+	assert.Equal(t, expected, syncer.status)
+
+	// the local chain itself must have advanced across every round-trip, not
+	// just stalled after the first fragment.
+	assert.Equal(t, headers[len(headers)-1].Number, syncer.blockchain.Header().Number)
+}
+
+func TestWarpSyncWithPeer_RejectsUnauthorizedFinality(t *testing.T) {
+	headers := blockchain.NewTestHeaderChainWithSeed(nil, 1000, 0)
+	genesis := headers[0]
+
+	chain := NewMockBlockchain([]*types.Header{genesis})
+	peerChain := NewMockBlockchain(headers)
+
+	syncer, peerSyncers := SetupSyncerNetwork(t, chain, []blockchainShim{peerChain})
+	syncer.SetValidatorSetVerifier(rejectingVerifier{})
+	syncer.SetGenesisValidators([]types.Address{})
+	peerSyncers[0].SetValidatorSetVerifier(permissiveVerifier{})
+
+	peer := getPeer(syncer, peerSyncers[0].server.AddrInfo().ID)
+	assert.NotNil(t, peer)
+
+	err := syncer.WarpSyncWithPeer(peer, genesis.Hash)
+	assert.Error(t, err)
+}